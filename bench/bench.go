@@ -0,0 +1,179 @@
+// Package bench compares the fib package's Fibonacci algorithms for
+// wall-clock time, allocations, and correctness, without depending on
+// go test.
+package bench
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"time"
+
+	"github.com/yosefmih/testserver/fib"
+)
+
+// maxRecursiveN caps the n at which the naive recursive algorithm is run;
+// past it the O(phi^n) blowup makes a benchmark pass take unreasonably long.
+const maxRecursiveN = 35
+
+// iterations is how many times each algorithm is re-run per n to damp
+// scheduling noise; Result reports the fastest run, matching testing.B's
+// convention of reporting best-case throughput.
+const iterations = 5
+
+// Result is one row of a benchmark comparison: one algorithm run against
+// one n.
+type Result struct {
+	Algorithm string
+	N         uint
+	Value     string
+	Elapsed   time.Duration
+	AllocsOp  uint64
+	Correct   bool
+	Skipped   bool
+}
+
+// Run benchmarks every algorithm in the fib package against each of ns,
+// cross-checking each result against the fast-doubling big-int
+// implementation, which is the one immune to int overflow.
+func Run(ns []uint) []Result {
+	var results []Result
+	for _, n := range ns {
+		reference := fib.Big(n)
+		results = append(results, benchBig(n, reference))
+		results = append(results, benchIterative(n, reference))
+		results = append(results, benchMemoized(n, reference))
+		if n <= maxRecursiveN {
+			results = append(results, benchRecursive(n, reference))
+		} else {
+			results = append(results, Result{Algorithm: "recursive", N: n, Skipped: true})
+		}
+	}
+	return results
+}
+
+func benchBig(n uint, reference *big.Int) Result {
+	var value *big.Int
+	elapsed, allocs := measure(func() { value = fib.Big(n) })
+	return Result{
+		Algorithm: "fast-doubling",
+		N:         n,
+		Value:     value.String(),
+		Elapsed:   elapsed,
+		AllocsOp:  allocs,
+		Correct:   value.Cmp(reference) == 0,
+	}
+}
+
+func benchIterative(n uint, reference *big.Int) Result {
+	var value int
+	elapsed, allocs := measure(func() { value = fib.Iterative(int(n)) })
+	return Result{
+		Algorithm: "iterative",
+		N:         n,
+		Value:     fmt.Sprint(value),
+		Elapsed:   elapsed,
+		AllocsOp:  allocs,
+		Correct:   big.NewInt(int64(value)).Cmp(reference) == 0,
+	}
+}
+
+func benchMemoized(n uint, reference *big.Int) Result {
+	var value int
+	elapsed, allocs := measure(func() { value = fib.Memoized(int(n)) })
+	return Result{
+		Algorithm: "memoized",
+		N:         n,
+		Value:     fmt.Sprint(value),
+		Elapsed:   elapsed,
+		AllocsOp:  allocs,
+		Correct:   big.NewInt(int64(value)).Cmp(reference) == 0,
+	}
+}
+
+func benchRecursive(n uint, reference *big.Int) Result {
+	var value int
+	elapsed, allocs := measure(func() { value = fib.Recursive(int(n)) })
+	return Result{
+		Algorithm: "recursive",
+		N:         n,
+		Value:     fmt.Sprint(value),
+		Elapsed:   elapsed,
+		AllocsOp:  allocs,
+		Correct:   big.NewInt(int64(value)).Cmp(reference) == 0,
+	}
+}
+
+// measure runs f iterations times, returning the fastest wall-clock time
+// observed and the heap allocations per call, sampled via runtime.MemStats.
+func measure(f func()) (time.Duration, uint64) {
+	var best time.Duration
+	var m0, m1 runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&m0)
+
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		f()
+		elapsed := time.Since(start)
+		if i == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+
+	runtime.ReadMemStats(&m1)
+	return best, (m1.Mallocs - m0.Mallocs) / iterations
+}
+
+// WriteTable writes results as an aligned, human-readable table.
+func WriteTable(w io.Writer, results []Result) {
+	fmt.Fprintf(w, "%-14s %8s %12s %10s %8s  %s\n", "algorithm", "n", "time", "allocs/op", "correct", "value")
+	for _, r := range results {
+		if r.Skipped {
+			fmt.Fprintf(w, "%-14s %8d %12s %10s %8s  %s\n", r.Algorithm, r.N, "-", "-", "-", "skipped (n too large)")
+			continue
+		}
+		fmt.Fprintf(w, "%-14s %8d %12s %10d %8t  %s\n", r.Algorithm, r.N, r.Elapsed, r.AllocsOp, r.Correct, truncate(r.Value, 24))
+	}
+}
+
+// WriteCSV writes results in CSV form so they can be plotted:
+// algorithm,n,elapsed_ns,allocs_per_op,correct,value.
+func WriteCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	if err := cw.Write([]string{"algorithm", "n", "elapsed_ns", "allocs_per_op", "correct", "value"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Skipped {
+			if err := cw.Write([]string{r.Algorithm, fmt.Sprint(r.N), "", "", "", "skipped"}); err != nil {
+				return err
+			}
+			continue
+		}
+		row := []string{
+			r.Algorithm,
+			fmt.Sprint(r.N),
+			fmt.Sprint(r.Elapsed.Nanoseconds()),
+			fmt.Sprint(r.AllocsOp),
+			fmt.Sprint(r.Correct),
+			r.Value,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return cw.Error()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
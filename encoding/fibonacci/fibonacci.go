@@ -0,0 +1,152 @@
+// Package fibonacci implements the Zeckendorf representation of a natural
+// number and the Fibonacci universal code built on top of it.
+package fibonacci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Zeckendorf returns, largest first, the indices k of the unique set of
+// non-consecutive Fibonacci numbers F(k) that sum to n, using the
+// numbering F(1)=1, F(2)=1, F(3)=2, F(4)=3, F(5)=5, .... It greedily
+// subtracts the largest F(k) not exceeding the remainder, which is what
+// guarantees the non-consecutive property.
+func Zeckendorf(n uint64) []int {
+	if n == 0 {
+		return nil
+	}
+
+	// fibs[i] holds F(i+2); starting at F(2) rather than F(1) avoids the
+	// duplicate F(1)==F(2)==1 that would otherwise make representations
+	// ambiguous.
+	fibs := []uint64{1, 2}
+	for {
+		next := fibs[len(fibs)-1] + fibs[len(fibs)-2]
+		if next > n {
+			break
+		}
+		fibs = append(fibs, next)
+	}
+
+	var indices []int
+	remaining := n
+	for i := len(fibs) - 1; i >= 0 && remaining > 0; i-- {
+		if fibs[i] <= remaining {
+			indices = append(indices, i+2)
+			remaining -= fibs[i]
+		}
+	}
+	return indices
+}
+
+// fibonacciCode returns the Fibonacci code word for v (v must be >= 1) as
+// the Zeckendorf bits, least-significant-Fibonacci first, with an extra 1
+// bit appended as a terminator. Because the highest-order Zeckendorf bit
+// is always 1, the terminator always produces a "11" codeword boundary.
+func fibonacciCode(v uint64) []bool {
+	indices := Zeckendorf(v)
+	maxIndex := indices[0]
+	bits := make([]bool, maxIndex-1) // positions for F(2) .. F(maxIndex)
+	for _, idx := range indices {
+		bits[idx-2] = true
+	}
+	return append(bits, true)
+}
+
+// valueOf sums F(i+2) for each set bit[i], the inverse of fibonacciCode's
+// bit layout (excluding the terminator bit, which the caller must strip).
+func valueOf(bits []bool) uint64 {
+	var a, b uint64 = 1, 2 // F(2), F(3)
+	var sum uint64
+	for _, bit := range bits {
+		if bit {
+			sum += a
+		}
+		a, b = b, a+b
+	}
+	return sum
+}
+
+// Encode reads bytes from r and writes their Fibonacci code to w as an
+// ASCII bitstring of '0'/'1' characters. Each byte v is coded as
+// Zeckendorf(v+1) so that the zero byte is representable; codewords are
+// written back to back and the characteristic "11" terminator is what
+// lets Decode recover the boundaries.
+func Encode(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, bit := range fibonacciCode(uint64(b) + 1) {
+			ch := byte('0')
+			if bit {
+				ch = '1'
+			}
+			if err := bw.WriteByte(ch); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// Decode reads an ASCII bitstring produced by Encode from r, scans for
+// "11" codeword boundaries, and writes the original bytes to w.
+func Decode(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+
+	var bits []bool
+	prevOne := false
+
+	flush := func() error {
+		v := valueOf(bits[:len(bits)-1]) // drop the terminator bit
+		if v == 0 {
+			return fmt.Errorf("fibonacci: invalid codeword decodes to 0")
+		}
+		bits = bits[:0]
+		return bw.WriteByte(byte(v - 1))
+	}
+
+	for {
+		ch, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch ch {
+		case '0':
+			bits = append(bits, false)
+			prevOne = false
+		case '1':
+			bits = append(bits, true)
+			if prevOne {
+				if err := flush(); err != nil {
+					return err
+				}
+				prevOne = false
+				continue
+			}
+			prevOne = true
+		default:
+			return fmt.Errorf("fibonacci: invalid bit character %q", ch)
+		}
+	}
+
+	if len(bits) != 0 {
+		return fmt.Errorf("fibonacci: truncated codeword")
+	}
+	return bw.Flush()
+}
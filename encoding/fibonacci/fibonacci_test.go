@@ -0,0 +1,68 @@
+package fibonacci
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestZeckendorf(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want []int
+	}{
+		{0, nil},
+		{1, []int{2}},
+		{2, []int{3}},
+		{4, []int{4, 2}},
+		{100, []int{11, 6, 4}}, // F(11)=89, F(6)=8, F(4)=3
+	}
+
+	for _, tt := range tests {
+		got := Zeckendorf(tt.n)
+		if len(got) != len(tt.want) {
+			t.Fatalf("Zeckendorf(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("Zeckendorf(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestZeckendorfNonConsecutive(t *testing.T) {
+	for n := uint64(1); n < 2000; n++ {
+		indices := Zeckendorf(n)
+		for i := 1; i < len(indices); i++ {
+			if indices[i-1]-indices[i] < 2 {
+				t.Fatalf("Zeckendorf(%d) = %v has consecutive indices", n, indices)
+			}
+		}
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		{},
+		{0},
+		{255},
+		[]byte("hello, world"),
+		[]byte{0, 1, 2, 3, 254, 255, 0, 128},
+	}
+
+	for _, in := range inputs {
+		var encoded bytes.Buffer
+		if err := Encode(bytes.NewReader(in), &encoded); err != nil {
+			t.Fatalf("Encode(%v): %v", in, err)
+		}
+
+		var decoded bytes.Buffer
+		if err := Decode(&encoded, &decoded); err != nil {
+			t.Fatalf("Decode(%v): %v", in, err)
+		}
+
+		if !bytes.Equal(decoded.Bytes(), in) {
+			t.Fatalf("round trip of %v produced %v", in, decoded.Bytes())
+		}
+	}
+}
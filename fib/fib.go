@@ -0,0 +1,120 @@
+// Package fib provides Fibonacci number computations.
+package fib
+
+import (
+	"context"
+	"math/big"
+)
+
+// Recursive calculates the nth Fibonacci number using naive recursion.
+func Recursive(n int) int {
+	if n <= 1 {
+		return n
+	}
+	return Recursive(n-1) + Recursive(n-2)
+}
+
+// Iterative calculates the nth Fibonacci number using iteration.
+func Iterative(n int) int {
+	if n <= 1 {
+		return n
+	}
+
+	prev, curr := 0, 1
+	for i := 2; i <= n; i++ {
+		prev, curr = curr, prev+curr
+	}
+	return curr
+}
+
+// Memoized calculates the nth Fibonacci number using top-down recursion
+// with memoization, trading the O(n) extra space of a cache for avoiding
+// Recursive's exponential blowup.
+func Memoized(n int) int {
+	memo := make(map[int]int, n+1)
+	var helper func(int) int
+	helper = func(n int) int {
+		if n <= 1 {
+			return n
+		}
+		if v, ok := memo[n]; ok {
+			return v
+		}
+		v := helper(n-1) + helper(n-2)
+		memo[n] = v
+		return v
+	}
+	return helper(n)
+}
+
+// Generator returns a closure that yields successive Fibonacci numbers,
+// starting at F(0), one call at a time. Unlike Big, it never recomputes
+// earlier terms, so callers that need a long run of values (e.g. a
+// streaming HTTP response) can consume them without holding the whole
+// sequence in memory.
+func Generator() func() *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	return func() *big.Int {
+		result := a
+		a, b = b, new(big.Int).Add(a, b)
+		return result
+	}
+}
+
+// Stream sends the first n Fibonacci numbers to out, in order starting at
+// F(0), and closes out when it has sent n values or ctx is canceled,
+// whichever comes first. It's meant to be run in its own goroutine, with
+// the caller ranging over out; callers that want back-pressure-bounded
+// throughput control it via out's buffer size.
+func Stream(ctx context.Context, out chan<- *big.Int, n int) {
+	defer close(out)
+
+	next := Generator()
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case out <- next():
+		}
+	}
+}
+
+// Big returns the nth Fibonacci number as an arbitrary-precision integer.
+// It uses the fast-doubling identities
+//
+//	F(2k)   = F(k) * (2*F(k+1) - F(k))
+//	F(2k+1) = F(k)^2 + F(k+1)^2
+//
+// to compute the result in O(log n) big.Int multiplications, which keeps
+// n in the millions tractable where the O(n) iterative approach would not be.
+func Big(n uint) *big.Int {
+	a, _ := fastDouble(n)
+	return a
+}
+
+// fastDouble returns the pair (F(k), F(k+1)) for k = n, recursing on n/2 and
+// combining with the fast-doubling identities.
+func fastDouble(n uint) (*big.Int, *big.Int) {
+	if n == 0 {
+		return big.NewInt(0), big.NewInt(1)
+	}
+
+	a, b := fastDouble(n / 2)
+
+	// t = 2*F(k+1) - F(k)
+	t := new(big.Int).Lsh(b, 1)
+	t.Sub(t, a)
+
+	// c = F(k) * t = F(2k)
+	c := new(big.Int).Mul(a, t)
+
+	// d = F(k)^2 + F(k+1)^2 = F(2k+1)
+	d := new(big.Int).Mul(a, a)
+	bSq := new(big.Int).Mul(b, b)
+	d.Add(d, bSq)
+
+	if n%2 == 0 {
+		return c, d
+	}
+	return d, new(big.Int).Add(c, d)
+}
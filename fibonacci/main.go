@@ -1,42 +1,137 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"log"
+	"math/big"
+	"net/http"
 	"os"
+	"os/signal"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yosefmih/testserver/bench"
+	"github.com/yosefmih/testserver/encoding/fibonacci"
+	"github.com/yosefmih/testserver/fib"
+	"github.com/yosefmih/testserver/server"
 )
 
-// fibRecursive calculates the nth Fibonacci number using recursion
-func fibRecursive(n int) int {
-	if n <= 1 {
-		return n
+// bigThreshold is the n above which results silently overflow a 64-bit int,
+// so we switch to the arbitrary-precision path automatically.
+const bigThreshold = 92
+
+// defaultBenchNs is the set of n values benched when none are given on the
+// command line; it spans from trivially small up through bigThreshold to
+// show where the int-based algorithms start to diverge from fast-doubling.
+var defaultBenchNs = []uint{10, 20, 30, 40, 50, 92, 100}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		case "zeckendorf":
+			runZeckendorf(os.Args[2:])
+			return
+		case "encode":
+			runEncode(os.Args[2:])
+			return
+		case "decode":
+			runDecode(os.Args[2:])
+			return
+		}
 	}
-	return fibRecursive(n-1) + fibRecursive(n-2)
+	runCompute(os.Args[1:])
 }
 
-// fibIterative calculates the nth Fibonacci number using iteration
-func fibIterative(n int) int {
-	if n <= 1 {
-		return n
+// negativeNumber matches a bare negative-integer argument, e.g. "-5".
+var negativeNumber = regexp.MustCompile(`^-\d+$`)
+
+// valueFlagNames returns, in both single- and double-dash spellings, the
+// names of fs's flags that consume the following argument as their value
+// (i.e. every flag except bool flags, which only take a value via
+// "-name=value").
+func valueFlagNames(fs *flag.FlagSet) map[string]bool {
+	type boolFlag interface {
+		IsBoolFlag() bool
 	}
 
-	prev, curr := 0, 1
-	for i := 2; i <= n; i++ {
-		prev, curr = curr, prev+curr
+	names := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		if bf, ok := f.Value.(boolFlag); ok && bf.IsBoolFlag() {
+			return
+		}
+		names["-"+f.Name] = true
+		names["--"+f.Name] = true
+	})
+	return names
+}
+
+// insertFlagTerminator inserts "--" right before the first bare negative
+// number in args that isn't one of valueFlags' arguments, so the flag
+// package treats it as a positional argument instead of an unrecognized
+// flag. If args already contains a literal "--" before that point, it's
+// left alone: flag.Parse will stop interpreting flags there anyway, so
+// inserting a second one would leave a stray "--" in fs.Args().
+func insertFlagTerminator(args []string, valueFlags map[string]bool) []string {
+	for i, a := range args {
+		if a == "--" {
+			return args
+		}
+		if i > 0 && valueFlags[args[i-1]] {
+			continue
+		}
+		if negativeNumber.MatchString(a) {
+			out := make([]string, 0, len(args)+1)
+			out = append(out, args[:i]...)
+			out = append(out, "--")
+			return append(out, args[i:]...)
+		}
 	}
-	return curr
+	return args
 }
 
-func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: go run main.go <n>")
+// runCompute implements the original CLI: print the nth Fibonacci number.
+func runCompute(args []string) {
+	fs := flag.NewFlagSet("testserver", flag.ExitOnError)
+	useBig := fs.Bool("big", false, "use arbitrary-precision computation (automatic for n > 92)")
+	stream := fs.Int("stream", 0, "print the first N Fibonacci numbers through a goroutine pipeline")
+	chanCap := fs.Int("chan-cap", 64, "buffered channel capacity for -stream")
+	fs.Parse(insertFlagTerminator(args, valueFlagNames(fs)))
+
+	if *stream > 0 {
+		if *chanCap < 1 {
+			fmt.Println("Error: -chan-cap must be at least 1")
+			os.Exit(1)
+		}
+		runStream(*stream, *chanCap)
+		return
+	}
+
+	rest := fs.Args()
+	if len(rest) != 1 {
+		fmt.Println("Usage: testserver [-big] <n>")
+		fmt.Println("       testserver [-stream N] [-chan-cap C]")
+		fmt.Println("       testserver serve [-port N]")
+		fmt.Println("       testserver bench [-csv] [n ...]")
+		fmt.Println("       testserver zeckendorf <n>")
+		fmt.Println("       testserver encode <text>")
+		fmt.Println("       testserver decode <bitstring>")
 		fmt.Println("where n is the position of the Fibonacci number to compute")
 		os.Exit(1)
 	}
 
-	n, err := strconv.Atoi(os.Args[1])
+	n, err := strconv.Atoi(rest[0])
 	if err != nil {
-		fmt.Printf("Error: Invalid number '%s'\n", os.Args[1])
+		fmt.Printf("Error: Invalid number '%s'\n", rest[0])
 		os.Exit(1)
 	}
 
@@ -45,7 +140,141 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *useBig || n > bigThreshold {
+		result := fib.Big(uint(n))
+		fmt.Printf("The %dth Fibonacci number is: %s\n", n, result.String())
+		return
+	}
+
 	// Using iterative method as it's more efficient
-	result := fibIterative(n)
+	result := fib.Iterative(n)
 	fmt.Printf("The %dth Fibonacci number is: %d\n", n, result)
 }
+
+// runStream prints the first n Fibonacci numbers as they arrive from a
+// producer goroutine, through a channel of the given capacity. SIGINT
+// cancels the pipeline's context so the producer tears down cleanly
+// instead of blocking on a send no one will read.
+func runStream(n, chanCap int) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	out := make(chan *big.Int, chanCap)
+	go fib.Stream(ctx, out, n)
+
+	for v := range out {
+		fmt.Println(v.String())
+	}
+}
+
+// runBench compares fib's algorithms against each other and prints a table
+// (or, with -csv, a CSV) of wall-clock time, allocations, and a
+// correctness cross-check against fast-doubling.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	csvOut := fs.Bool("csv", false, "output results as CSV instead of a table")
+	fs.Parse(args)
+
+	ns := defaultBenchNs
+	if rest := fs.Args(); len(rest) > 0 {
+		ns = make([]uint, len(rest))
+		for i, a := range rest {
+			v, err := strconv.ParseUint(a, 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid number '%s'\n", a)
+				os.Exit(1)
+			}
+			ns[i] = uint(v)
+		}
+	}
+
+	results := bench.Run(ns)
+	if *csvOut {
+		if err := bench.WriteCSV(os.Stdout, results); err != nil {
+			log.Fatalf("write csv: %v", err)
+		}
+		return
+	}
+	bench.WriteTable(os.Stdout, results)
+}
+
+// runZeckendorf prints n's unique representation as a sum of
+// non-consecutive Fibonacci numbers.
+func runZeckendorf(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: testserver zeckendorf <n>")
+		os.Exit(1)
+	}
+
+	n, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Error: Invalid number '%s'\n", args[0])
+		os.Exit(1)
+	}
+
+	indices := fibonacci.Zeckendorf(n)
+	terms := make([]string, len(indices))
+	for i, idx := range indices {
+		terms[i] = fmt.Sprintf("F(%d)", idx)
+	}
+	fmt.Printf("%d = %s\n", n, strings.Join(terms, " + "))
+}
+
+// runEncode prints the Fibonacci code of text's bytes as an ASCII
+// bitstring.
+func runEncode(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: testserver encode <text>")
+		os.Exit(1)
+	}
+
+	if err := fibonacci.Encode(strings.NewReader(args[0]), os.Stdout); err != nil {
+		log.Fatalf("encode: %v", err)
+	}
+	fmt.Println()
+}
+
+// runDecode prints the bytes decoded from a Fibonacci-coded ASCII
+// bitstring.
+func runDecode(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: testserver decode <bitstring>")
+		os.Exit(1)
+	}
+
+	if err := fibonacci.Decode(strings.NewReader(args[0]), os.Stdout); err != nil {
+		log.Fatalf("decode: %v", err)
+	}
+	fmt.Println()
+}
+
+// runServe starts the HTTP server and blocks until it shuts down cleanly on
+// SIGINT.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	port := fs.Int("port", 8080, "port to listen on")
+	fs.Parse(args)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: server.New(),
+	}
+
+	go func() {
+		log.Printf("listening on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("serve: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	<-stop
+
+	log.Println("shutting down...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("shutdown: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package server exposes Fibonacci computations as an HTTP API.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/yosefmih/testserver/fib"
+)
+
+// streamChanCap is the buffer size of the channel fib.Stream feeds the
+// streaming handlers through.
+const streamChanCap = 16
+
+// maxN bounds the n accepted by /fib and the limit accepted by /seq and
+// /seq/sse. Big-int multiplication cost grows worse than linearly with
+// digit count, so without a cap a single request for a huge n or limit
+// can pin a CPU core and allocate unbounded memory for minutes.
+const maxN = 500_000
+
+// New returns an http.Handler serving the Fibonacci API:
+//
+//	GET /healthz   liveness check
+//	GET /fib/{n}   the nth Fibonacci number, as JSON
+//	GET /seq       the first {limit} terms, newline-delimited JSON
+//	GET /seq/sse   the first {limit} terms, as Server-Sent Events
+func New() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/fib/", handleFib)
+	mux.HandleFunc("/seq", handleSeq)
+	mux.HandleFunc("/seq/sse", handleSeqSSE)
+	return withRequestLogging(mux)
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "ok")
+}
+
+type fibResponse struct {
+	N         uint    `json:"n"`
+	Value     string  `json:"value"`
+	Algorithm string  `json:"algorithm"`
+	ElapsedMS float64 `json:"elapsed_ms"`
+}
+
+func handleFib(w http.ResponseWriter, r *http.Request) {
+	nStr := strings.TrimPrefix(r.URL.Path, "/fib/")
+	n, err := strconv.ParseUint(nStr, 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid n %q", nStr), http.StatusBadRequest)
+		return
+	}
+	if n > maxN {
+		http.Error(w, fmt.Sprintf("n %d exceeds the maximum of %d", n, maxN), http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	value := fib.Big(uint(n))
+	elapsed := time.Since(start)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fibResponse{
+		N:         uint(n),
+		Value:     value.String(),
+		Algorithm: "fast-doubling",
+		ElapsedMS: float64(elapsed.Microseconds()) / 1000,
+	})
+}
+
+// handleSeq streams the first {limit} Fibonacci numbers as newline-delimited
+// JSON, flushing after each term so the client can consume the response
+// before it completes.
+func handleSeq(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	out := make(chan *big.Int, streamChanCap)
+	go fib.Stream(r.Context(), out, limit)
+
+	enc := json.NewEncoder(w)
+	for v := range out {
+		if err := enc.Encode(v); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// handleSeqSSE is the Server-Sent Events analog of handleSeq, for browsers
+// that want to consume an open-ended stream with EventSource.
+func handleSeqSSE(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	out := make(chan *big.Int, streamChanCap)
+	go fib.Stream(r.Context(), out, limit)
+
+	for v := range out {
+		fmt.Fprintf(w, "data: %s\n\n", v.String())
+		flusher.Flush()
+	}
+}
+
+func parseLimit(r *http.Request) (int, error) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return 0, fmt.Errorf("missing required query parameter %q", "limit")
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid limit %q", limitStr)
+	}
+	if limit > maxN {
+		return 0, fmt.Errorf("limit %d exceeds the maximum of %d", limit, maxN)
+	}
+	return limit, nil
+}
+
+var requestCounter int64
+
+// withRequestLogging assigns each incoming request a unique, monotonically
+// increasing ID and logs its method, path, and elapsed time.
+func withRequestLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt64(&requestCounter, 1)
+		start := time.Now()
+		log.Printf("[req %d] %s %s", id, r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+		log.Printf("[req %d] %s %s completed in %s", id, r.Method, r.URL.Path, time.Since(start))
+	})
+}